@@ -12,6 +12,7 @@ import (
 	"os"
 	"path/filepath"
 	"runtime/trace"
+	"sort"
 	"time"
 
 	"github.com/rusq/dlog"
@@ -19,10 +20,24 @@ import (
 
 	"github.com/rusq/slackdump/v2"
 	"github.com/rusq/slackdump/v2/downloader"
+	"github.com/rusq/slackdump/v2/internal/exporter"
 )
 
+func init() {
+	exporter.Register("slack", func(dir string, dumper *slackdump.SlackDumper, opts exporter.Options) (exporter.Exporter, error) {
+		return New(dir, dumper, Options{
+			Oldest:         opts.Oldest,
+			Latest:         opts.Latest,
+			IncludeFiles:   opts.IncludeFiles,
+			IncludePrivate: opts.IncludePrivate,
+		}), nil
+	})
+}
+
 const userPrefix = "IM-" // prefix for Direct Messages
 
+var _ exporter.Exporter = (*Export)(nil)
+
 // Export is the instance of Slack Exporter.
 type Export struct {
 	dir    string                 //target directory
@@ -30,20 +45,56 @@ type Export struct {
 
 	// time window
 	opts Options
+
+	// state is the resumable-export state loaded from (and flushed back to)
+	// state.json in dir.
+	state *exportState
 }
 
 type Options struct {
 	Oldest       time.Time
 	Latest       time.Time
 	IncludeFiles bool
+
+	// IncludePrivate, when set, makes Run also fetch private channels
+	// ("groups") the authenticated user belongs to and write them to
+	// groups.json alongside channels.json.
+	IncludePrivate bool
+
+	// Since, if set, overrides any latest_ts recorded in state.json,
+	// forcing every channel to resume from this point instead.
+	Since time.Time
+
+	// ThreadsOnly, when set, drops every message that isn't part of a
+	// thread (neither a root with replies nor a reply itself), so Run only
+	// exports threaded conversations.
+	ThreadsOnly bool
+
+	// MaxThreadDepth caps how many replies of each thread are embedded and
+	// written out; the whole reply chain is still fetched. Zero means
+	// unlimited.
+	MaxThreadDepth int
 }
 
 func New(dir string, dumper *slackdump.SlackDumper, cfg Options) *Export {
-	return &Export{dir: dir, dumper: dumper, opts: cfg}
+	return &Export{
+		dir:    dir,
+		dumper: dumper,
+		opts:   cfg,
+		state:  &exportState{path: filepath.Join(dir, stateFilename), Channels: make(map[string]*channelState)},
+	}
 }
 
-// Run runs the export.
+// Run runs the export.  If dir already contains a state.json from a
+// previous, interrupted run, it resumes each channel from its recorded
+// latest_ts instead of starting over.
 func (se *Export) Run(ctx context.Context) error {
+	state, err := loadState(filepath.Join(se.dir, stateFilename))
+	if err != nil {
+		return err
+	}
+	se.state = state
+
 	// export users to users.json
 	users, err := se.users(ctx)
 	if err != nil {
@@ -54,9 +105,42 @@ func (se *Export) Run(ctx context.Context) error {
 	if err := se.messages(ctx, users); err != nil {
 		return err
 	}
+
+	// export private channels to groups.json, if requested
+	if err := se.groups(ctx, users); err != nil {
+		return err
+	}
 	return nil
 }
 
+// groups fetches the private channels the authenticated user belongs to and
+// writes them, together with their messages, in the same layout messages
+// uses for public channels.  It is a no-op unless Options.IncludePrivate is
+// set.
+func (se *Export) groups(ctx context.Context, users slackdump.Users) error {
+	if !se.opts.IncludePrivate {
+		return nil
+	}
+
+	groups, err := se.dumper.FetchPrivateChannels(ctx)
+	if err != nil {
+		return fmt.Errorf("groups: error fetching private channels: %w", err)
+	}
+
+	dl := downloader.New(se.dumper.Client())
+	if se.opts.IncludeFiles {
+		dl.Start(ctx)
+	}
+
+	for _, ch := range groups {
+		if err := se.exportConversation(ctx, ch, users, dl); err != nil {
+			return fmt.Errorf("groups: %w", err)
+		}
+	}
+
+	return serializeToFile(filepath.Join(se.dir, "groups.json"), groups)
+}
+
 func (se *Export) users(ctx context.Context) (slackdump.Users, error) {
 	// fetch users and save them.
 	users, err := se.dumper.GetUsers(ctx)
@@ -107,19 +191,40 @@ func filterOutStrangeChannels(chans []slack.Channel) []slack.Channel {
 	chansFiltered := []slack.Channel{}
 
 	for i := range chans {
-		if chans[i].Name != "" && chans[i].NameNormalized != "" {
-			chansFiltered = append(chansFiltered, chans[i])
-		} else {
-			dlog.Printf("Filter out a channel of: %s", chans[i].User)
+		ch := chans[i]
+		if !ch.IsPrivate {
+			if ch.Name == "" || ch.NameNormalized == "" {
+				dlog.Printf("Filter out a channel of: %s", ch.User)
+				continue
+			}
+		} else if ch.Name == "" && ch.NameNormalized == "" {
+			// private channels are always worth keeping, even when the API
+			// didn't return a name for them.
+			ch.Name = fallbackChannelName(ch)
+			ch.NameNormalized = ch.Name
 		}
+		chansFiltered = append(chansFiltered, ch)
 	}
 	return chansFiltered
 }
 
+// fallbackChannelName synthesises a directory-safe name for a channel the
+// API returned without one, preferring NameNormalized over the channel ID.
+func fallbackChannelName(ch slack.Channel) string {
+	if ch.NameNormalized != "" {
+		return ch.NameNormalized
+	}
+	return ch.ID
+}
+
 func (se *Export) exportConversation(ctx context.Context, ch slack.Channel, users slackdump.Users, dl *downloader.Client) error {
+	oldest, err := se.resumeOldest(ch.ID)
+	if err != nil {
+		return fmt.Errorf("failed resuming %q (%s): %w", ch.Name, ch.ID, err)
+	}
 
 	dlFn := se.downloadFn(dl, ch.Name)
-	messages, err := se.dumper.DumpMessagesRaw(ctx, ch.ID, se.opts.Oldest, se.opts.Latest, dlFn)
+	messages, err := se.dumper.DumpMessagesRaw(ctx, ch.ID, oldest, se.opts.Latest, dlFn)
 	if err != nil {
 		return fmt.Errorf("failed dumping %q (%s): %w", ch.Name, ch.ID, err)
 	}
@@ -133,25 +238,99 @@ func (se *Export) exportConversation(ctx context.Context, ch slack.Channel, user
 		return fmt.Errorf("exportChannelData: error: %w", err)
 	}
 
+	if se.opts.ThreadsOnly {
+		msgs = filterThreadsOnly(msgs)
+		if len(msgs) == 0 {
+			return nil
+		}
+	}
+
+	if err := se.attachThreads(ctx, ch, msgs, dl); err != nil {
+		return fmt.Errorf("thread expansion for %q: %w", ch.Name, err)
+	}
+
 	name, err := validName(ctx, ch, users.IndexByID())
 	if err != nil {
 		return err
 	}
 
-	if err := se.saveChannel(name, msgs); err != nil {
+	if err := se.saveChannel(name, msgs, se.state.completedDateSet(ch.ID)); err != nil {
 		return err
 	}
 
+	latest := latestTimestamp(messages.Messages)
+	finalized, err := finalizedDates(msgs, latest)
+	if err != nil {
+		return fmt.Errorf("failed finalizing dates for %q: %w", ch.Name, err)
+	}
+	se.state.noteLatest(ch.ID, latest)
+	se.state.noteCompletedDates(ch.ID, finalized)
+	if err := se.state.flush(); err != nil {
+		return fmt.Errorf("failed flushing state for %q: %w", ch.Name, err)
+	}
+
 	return nil
 }
 
+// resumeOldest picks the oldest timestamp exportConversation should request
+// for channelID: Options.Since, if set, always wins; otherwise the
+// channel's recorded latest_ts resumes it past what a previous, interrupted
+// run already saved; failing both, it falls back to Options.Oldest.
+func (se *Export) resumeOldest(channelID string) (time.Time, error) {
+	if !se.opts.Since.IsZero() {
+		return se.opts.Since, nil
+	}
+
+	resumed, err := tsToOldest(se.state.get(channelID).LatestTS)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if resumed.After(se.opts.Oldest) {
+		return resumed, nil
+	}
+	return se.opts.Oldest, nil
+}
+
+// finalizedDates returns the keys of msgs that are safe to record as
+// completed in state.json, i.e. every date strictly before the one latestTS
+// falls on. The date latestTS itself falls on is excluded: since
+// conversations.history pages newest-first, the same day can still receive
+// earlier-arriving messages that this run's window already passed, so it
+// isn't final until a later run's latestTS moves past it.
+func finalizedDates(msgs messagesByDate, latestTS string) ([]string, error) {
+	latestDate, err := tsToDate(latestTS)
+	if err != nil {
+		return nil, err
+	}
+	dates := make([]string, 0, len(msgs))
+	for date := range msgs {
+		// YYYY-MM-DD sorts chronologically as a string, so a plain
+		// less-than comparison is enough here.
+		if date < latestDate {
+			dates = append(dates, date)
+		}
+	}
+	return dates, nil
+}
+
 // downloadFn returns the process function that should be passed to
 // DumpMessagesRaw that will handle the download of the files.  If the
 // downloader is not started, i.e. if file download is disabled, it will
 // silently ignore the error and return nil.
+//
+// It deliberately does not touch state.json: DumpMessagesRaw's pages arrive
+// newest-first, so the page seen first is the closest to "now", not the
+// channel's actual history boundary. Treating a page's timestamps as a safe
+// resume point here would let an interrupted run record a latest_ts newer
+// than anything actually persisted (saveChannel only runs once, after every
+// page for the whole window has been fetched) -- and since a later run's
+// oldest is taken from that latest_ts, it would then never re-request, and
+// so permanently lose, every older page that hadn't been saved yet.
+// latest_ts is only safe to advance in exportConversation, once saveChannel
+// has actually written the whole channel to disk.
 func (se *Export) downloadFn(dl *downloader.Client, channelName string) func(msg []slackdump.Message, channelID string) (slackdump.ProcessResult, error) {
 	dir := filepath.Join(se.basedir(channelName), "attachments")
-	return func(msg []slackdump.Message, channelID string) (slackdump.ProcessResult, error) {
+	return func(msg []slackdump.Message, _ string) (slackdump.ProcessResult, error) {
 		files := se.dumper.ExtractFiles(msg)
 		for _, f := range files {
 			if err := dl.DownloadFile(dir, f); err != nil {
@@ -225,22 +404,79 @@ func (se *Export) basedir(channelName string) string {
 }
 
 // saveChannel creates a directory `name` and writes the contents of msgs. for
-// each map key the json file is created, with the name `{key}.json`, and values
-// for that key are serialised to the file in json format.
-func (se *Export) saveChannel(channelName string, msgs messagesByDate) error {
+// each map key the json file is created, with the name `{key}.json`. If that
+// file already exists (from a previous, interrupted run), its messages are
+// merged with the new ones, de-duplicated by ts, sorted, and the result is
+// written atomically via a temp-file rename. Dates already present in
+// completed are skipped entirely: they were finalized by a previous run and
+// can no longer gain or lose messages, so there's nothing to merge.
+func (se *Export) saveChannel(channelName string, msgs messagesByDate, completed map[string]bool) error {
 	basedir := se.basedir(channelName)
 	if err := os.MkdirAll(basedir, 0700); err != nil {
 		return fmt.Errorf("unable to create directory %q: %w", channelName, err)
 	}
 	for date, messages := range msgs {
+		if completed[date] {
+			continue
+		}
 		output := filepath.Join(basedir, date+".json")
-		if err := serializeToFile(output, messages); err != nil {
+		merged, err := mergeDateFile(output, messages)
+		if err != nil {
+			return fmt.Errorf("unable to merge %q: %w", output, err)
+		}
+		if err := atomicSerializeToFile(output, merged); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
+// mergeDateFile merges incoming into the messages already saved at path,
+// de-duplicating by ts and sorting the result. If nothing is saved at path
+// yet, incoming is returned as is, neither de-duplicated nor sorted.
+func mergeDateFile(path string, incoming []slackdump.Message) ([]slackdump.Message, error) {
+	existing, err := readDateFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) == 0 {
+		return incoming, nil
+	}
+
+	byTS := make(map[string]slackdump.Message, len(existing)+len(incoming))
+	for _, m := range existing {
+		byTS[m.Timestamp] = m
+	}
+	for _, m := range incoming {
+		byTS[m.Timestamp] = m
+	}
+
+	merged := make([]slackdump.Message, 0, len(byTS))
+	for _, m := range byTS {
+		merged = append(merged, m)
+	}
+	sort.Slice(merged, func(i, j int) bool { return merged[i].Timestamp < merged[j].Timestamp })
+	return merged, nil
+}
+
+// readDateFile reads a previously-saved per-date message file, returning nil
+// if it doesn't exist yet.
+func readDateFile(path string) ([]slackdump.Message, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var msgs []slackdump.Message
+	if err := json.NewDecoder(f).Decode(&msgs); err != nil {
+		return nil, fmt.Errorf("failed to parse %q: %w", path, err)
+	}
+	return msgs, nil
+}
+
 // serializeToFile writes the data in json format to provided filename.
 func serializeToFile(filename string, data any) error {
 	f, err := os.Create(filename)
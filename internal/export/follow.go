@@ -0,0 +1,184 @@
+package export
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/slack-go/slack"
+
+	"github.com/rusq/slackdump/v2"
+	"github.com/rusq/slackdump/v2/downloader"
+)
+
+// Follow runs Export in a continuously-updating mode: it opens an RTM
+// connection via dumper.Stream and appends every "message", "message_changed"
+// and "message_deleted" event into the same per-channel, per-date JSON files
+// Run produces, and, when IncludeFiles is set, downloads the file of every
+// "file_shared" event into the channel's attachments -- turning the
+// export into a live mirror instead of a one-shot dump.
+func (se *Export) Follow(ctx context.Context) error {
+	users, err := se.dumper.GetUsers(ctx)
+	if err != nil {
+		return fmt.Errorf("follow: users: %w", err)
+	}
+
+	dl := downloader.New(se.dumper.Client())
+	if se.opts.IncludeFiles {
+		dl.Start(ctx)
+	}
+
+	f := &follower{se: se, uidx: users.IndexByID(), dl: dl, names: make(map[string]string)}
+
+	return se.dumper.Stream(ctx, func(evt slack.RTMEvent) error {
+		switch data := evt.Data.(type) {
+		case *slack.MessageEvent:
+			return f.message(ctx, (*slack.Message)(data))
+		case *slack.FileSharedEvent:
+			return f.fileShared(ctx, data)
+		default:
+			return nil
+		}
+	})
+}
+
+// follower holds the state Follow needs across events: the user index (for
+// validName), the file downloader, and a cache of channel ID -> export
+// directory name so repeated events for the same channel don't re-resolve
+// it every time.
+type follower struct {
+	se   *Export
+	uidx userIndex
+	dl   *downloader.Client
+
+	mu    sync.Mutex
+	names map[string]string
+}
+
+func (f *follower) channelName(ctx context.Context, channelID string) (string, error) {
+	f.mu.Lock()
+	if name, ok := f.names[channelID]; ok {
+		f.mu.Unlock()
+		return name, nil
+	}
+	f.mu.Unlock()
+
+	ch, err := f.se.dumper.Client().GetConversationInfoContext(ctx, channelID, false)
+	if err != nil {
+		return "", fmt.Errorf("follow: lookup %s: %w", channelID, err)
+	}
+	name, err := validName(ctx, *ch, f.uidx)
+	if err != nil {
+		return "", err
+	}
+
+	f.mu.Lock()
+	f.names[channelID] = name
+	f.mu.Unlock()
+	return name, nil
+}
+
+func (f *follower) message(ctx context.Context, msg *slack.Message) error {
+	name, err := f.channelName(ctx, msg.Channel)
+	if err != nil {
+		return err
+	}
+
+	switch msg.SubType {
+	case "message_changed":
+		if msg.SubMessage == nil {
+			return nil
+		}
+		return f.rewrite(name, *msg.SubMessage)
+	case "message_deleted":
+		return f.tombstone(name, msg.DeletedTimestamp)
+	default:
+		return f.append(name, msg.Msg)
+	}
+}
+
+// append adds msg to its date file, merging with whatever is already there
+// (mergeOne replaces an entry with the same ts, so a duplicated RTM delivery
+// is a no-op).
+func (f *follower) append(channelName string, msg slack.Msg) error {
+	date, err := tsToDate(msg.Timestamp)
+	if err != nil {
+		return fmt.Errorf("follow: %w", err)
+	}
+	return f.rewriteDateFile(channelName, date, func(msgs []slackdump.Message) []slackdump.Message {
+		return mergeOne(msgs, slackdump.Message{Message: slack.Message{Msg: msg}})
+	})
+}
+
+// rewrite replaces the entry matching msg.Timestamp in-place, used for
+// "message_changed" events.
+func (f *follower) rewrite(channelName string, msg slack.Msg) error {
+	date, err := tsToDate(msg.Timestamp)
+	if err != nil {
+		return fmt.Errorf("follow: %w", err)
+	}
+	return f.rewriteDateFile(channelName, date, func(msgs []slackdump.Message) []slackdump.Message {
+		return mergeOne(msgs, slackdump.Message{Message: slack.Message{Msg: msg}})
+	})
+}
+
+// tombstone replaces the entry at ts with a minimal {subtype:
+// "message_deleted"} stub, used for "message_deleted" events.
+func (f *follower) tombstone(channelName, ts string) error {
+	date, err := tsToDate(ts)
+	if err != nil {
+		return fmt.Errorf("follow: %w", err)
+	}
+	tombstone := slackdump.Message{Message: slack.Message{Msg: slack.Msg{
+		Type:      "message",
+		SubType:   "message_deleted",
+		Timestamp: ts,
+	}}}
+	return f.rewriteDateFile(channelName, date, func(msgs []slackdump.Message) []slackdump.Message {
+		return mergeOne(msgs, tombstone)
+	})
+}
+
+func (f *follower) fileShared(ctx context.Context, evt *slack.FileSharedEvent) error {
+	if !f.se.opts.IncludeFiles {
+		return nil
+	}
+	name, err := f.channelName(ctx, evt.ChannelID)
+	if err != nil {
+		return err
+	}
+	file, _, _, err := f.se.dumper.Client().GetFileInfoContext(ctx, evt.FileID, 0, 0)
+	if err != nil {
+		return fmt.Errorf("follow: file %s: %w", evt.FileID, err)
+	}
+	return f.dl.DownloadFile(filepath.Join(f.se.basedir(name), "attachments"), *file)
+}
+
+// rewriteDateFile reads the existing date file (if any), applies mutate to
+// its messages, and writes the result back atomically.
+func (f *follower) rewriteDateFile(channelName, date string, mutate func([]slackdump.Message) []slackdump.Message) error {
+	basedir := f.se.basedir(channelName)
+	if err := os.MkdirAll(basedir, 0700); err != nil {
+		return fmt.Errorf("follow: unable to create directory %q: %w", channelName, err)
+	}
+	path := filepath.Join(basedir, date+".json")
+
+	existing, err := readDateFile(path)
+	if err != nil {
+		return fmt.Errorf("follow: %w", err)
+	}
+	return atomicSerializeToFile(path, mutate(existing))
+}
+
+// mergeOne inserts or replaces, by ts, a single message in msgs.
+func mergeOne(msgs []slackdump.Message, m slackdump.Message) []slackdump.Message {
+	for i := range msgs {
+		if msgs[i].Timestamp == m.Timestamp {
+			msgs[i] = m
+			return msgs
+		}
+	}
+	return append(msgs, m)
+}
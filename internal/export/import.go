@@ -0,0 +1,403 @@
+package export
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"regexp"
+	"runtime/trace"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/slack-go/slack"
+
+	"github.com/rusq/slackdump/v2"
+)
+
+// rawChannel, rawUser and rawPost mirror the JSON shapes used by the
+// official Slack export (see Mattermost's slackimport for the reference
+// implementation), a smaller and slightly differently-shaped set of fields
+// than slack-go's own API types.
+type rawChannel struct {
+	Id      string   `json:"id"`
+	Name    string   `json:"name"`
+	Members []string `json:"members"`
+	Topic   rawTopic `json:"topic"`
+	Purpose rawTopic `json:"purpose"`
+}
+
+type rawTopic struct {
+	Value string `json:"value"`
+}
+
+type rawUser struct {
+	Id      string     `json:"id"`
+	Name    string     `json:"name"`
+	Profile rawProfile `json:"profile"`
+}
+
+type rawProfile struct {
+	RealName string `json:"real_name"`
+	Email    string `json:"email"`
+}
+
+type rawPost struct {
+	User      string      `json:"user"`
+	BotId     string      `json:"bot_id"`
+	Username  string      `json:"username"`
+	Text      string      `json:"text"`
+	TimeStamp string      `json:"ts"`
+	Type      string      `json:"type"`
+	SubType   string      `json:"subtype"`
+	Comment   *rawComment `json:"comment,omitempty"`
+}
+
+type rawComment struct {
+	Comment string `json:"comment"`
+}
+
+// dateFileRE matches the per-channel date files that the official Slack
+// export (and Export.saveChannel) produce, e.g. "2022-03-01.json".
+var dateFileRE = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}\.json$`)
+
+// defaultSkipSubtypes lists the message subtypes that are dropped on import
+// unless the caller asks for them explicitly.
+func defaultSkipSubtypes() map[string]bool {
+	return map[string]bool{
+		"bot_message":  true,
+		"file_comment": true,
+		"channel_join": true,
+	}
+}
+
+// Import reads an official Slack workspace export (a ZIP archive containing
+// channels.json, users.json, groups.json, dms.json, mpims.json and a
+// directory of YYYY-MM-DD.json files per conversation) and turns it back
+// into the same shapes that Export produces.  This allows two exports to be
+// diffed, or an edited export to be re-exported.
+type Import struct {
+	src fs.FS
+
+	// SkipSubtypes lists message subtypes to drop while importing, e.g.
+	// "bot_message", "file_comment", "channel_join".  Defaults to those
+	// three if left nil.
+	SkipSubtypes map[string]bool
+}
+
+// NewImport opens the Slack export ZIP at zipPath.  The returned close
+// function must be called once the Import is no longer needed.
+func NewImport(zipPath string) (*Import, func() error, error) {
+	zr, err := zip.OpenReader(zipPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("import: failed to open %q: %w", zipPath, err)
+	}
+	return &Import{src: zr, SkipSubtypes: defaultSkipSubtypes()}, zr.Close, nil
+}
+
+// Run converts the export referenced by im into the Export directory layout
+// rooted at dest: users.json, channels.json and one directory per channel
+// containing the per-date message files.
+func (im *Import) Run(ctx context.Context, dest string) error {
+	_, task := trace.NewTask(ctx, "Import.Run")
+	defer task.End()
+
+	users, err := im.users()
+	if err != nil {
+		return fmt.Errorf("import: users: %w", err)
+	}
+	if err := serializeToFile(filepath.Join(dest, "users.json"), users); err != nil {
+		return err
+	}
+
+	channels, err := im.channels()
+	if err != nil {
+		return fmt.Errorf("import: channels: %w", err)
+	}
+
+	se := &Export{dir: dest}
+	uidx := users.IndexByID()
+	for _, ch := range channels {
+		msgs, err := im.posts(ch)
+		if err != nil {
+			return fmt.Errorf("import: posts for %q: %w", ch.Name, err)
+		}
+		if len(msgs) == 0 {
+			continue
+		}
+
+		byDate, err := groupPostsByDate(msgs)
+		if err != nil {
+			return fmt.Errorf("import: %q: %w", ch.Name, err)
+		}
+
+		name, err := validName(ctx, ch, uidx)
+		if err != nil {
+			return err
+		}
+		if err := se.saveChannel(name, byDate, nil); err != nil {
+			return err
+		}
+	}
+
+	return serializeToFile(filepath.Join(dest, "channels.json"), channels)
+}
+
+// users decodes users.json into slackdump.Users.
+func (im *Import) users() (slackdump.Users, error) {
+	var raw []rawUser
+	if err := im.decodeJSON("users.json", &raw); err != nil {
+		return nil, err
+	}
+
+	users := make(slackdump.Users, 0, len(raw))
+	for _, u := range raw {
+		users = append(users, slack.User{
+			ID:   u.Id,
+			Name: u.Name,
+			Profile: slack.UserProfile{
+				RealName: u.Profile.RealName,
+				Email:    u.Profile.Email,
+			},
+		})
+	}
+	return users, nil
+}
+
+// channels decodes channels.json, groups.json, dms.json and mpims.json into
+// a single slice of slack.Channel, normalising names as validName expects.
+func (im *Import) channels() ([]slack.Channel, error) {
+	var all []slack.Channel
+
+	for _, name := range []string{"channels.json", "groups.json"} {
+		var raw []rawChannel
+		if err := im.decodeJSON(name, &raw); err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			return nil, err
+		}
+		for _, c := range raw {
+			all = append(all, slack.Channel{
+				GroupConversation: slack.GroupConversation{
+					Name: c.Name,
+					Conversation: slack.Conversation{
+						ID: c.Id,
+					},
+					Members: c.Members,
+					Topic:   slack.Topic{Value: c.Topic.Value},
+					Purpose: slack.Purpose{Value: c.Purpose.Value},
+				},
+				IsChannel: true,
+			})
+			all[len(all)-1].NameNormalized = normalizeChannelName(c.Id, c.Name)
+		}
+	}
+
+	for _, name := range []string{"dms.json", "mpims.json"} {
+		var raw []rawChannel
+		if err := im.decodeJSON(name, &raw); err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			return nil, err
+		}
+
+		// The exporting user is whichever member ID appears in every DM --
+		// they're the one constant party across all of their own
+		// conversations.
+		self := commonMember(raw)
+
+		for _, c := range raw {
+			user := counterpart(c.Members, self)
+			if user == "" {
+				// couldn't tell who the other party is (e.g. Members is
+				// missing or too short) -- fall back to the channel ID, as
+				// before.
+				user = c.Id
+			}
+			all = append(all, slack.Channel{
+				GroupConversation: slack.GroupConversation{
+					Conversation: slack.Conversation{ID: c.Id, User: user},
+					Members:      c.Members,
+				},
+				IsIM: true,
+			})
+		}
+	}
+
+	return all, nil
+}
+
+// commonMember returns the member ID present in every one of chans' Members
+// lists, or "" if chans is empty or no such ID exists.
+func commonMember(chans []rawChannel) string {
+	if len(chans) == 0 {
+		return ""
+	}
+	counts := make(map[string]int)
+	for _, c := range chans {
+		for _, m := range c.Members {
+			counts[m]++
+		}
+	}
+	for id, n := range counts {
+		if n == len(chans) {
+			return id
+		}
+	}
+	return ""
+}
+
+// counterpart returns the first entry in members that isn't self, i.e. the
+// other party in a DM, falling back to "" if members has no other entry.
+func counterpart(members []string, self string) string {
+	for _, m := range members {
+		if m != self {
+			return m
+		}
+	}
+	return ""
+}
+
+// posts reads every YYYY-MM-DD.json file in ch's export directory and
+// decodes them into slack.Message, dropping subtypes in im.SkipSubtypes.
+func (im *Import) posts(ch slack.Channel) ([]slack.Message, error) {
+	// The ZIP's directory is named after the channel's raw, un-normalized
+	// name (exactly as channels.json/groups.json wrote it), not
+	// ch.NameNormalized: normalizeChannelName rewrites names the official
+	// export never renamed on disk, so resolving against it here would miss
+	// the actual directory and silently import those channels as empty.
+	dir := ch.ID
+	if ch.Name != "" {
+		dir = ch.Name
+	}
+
+	entries, err := fs.ReadDir(im.src, dir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	skip := im.SkipSubtypes
+	if skip == nil {
+		skip = defaultSkipSubtypes()
+	}
+
+	var msgs []slack.Message
+	for _, e := range entries {
+		if e.IsDir() || !dateFileRE.MatchString(e.Name()) {
+			continue
+		}
+		var raw []rawPost
+		if err := im.decodeJSON(path.Join(dir, e.Name()), &raw); err != nil {
+			return nil, err
+		}
+		for _, p := range raw {
+			if skip[p.SubType] {
+				continue
+			}
+			ts, err := parseExportTS(p.TimeStamp)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", e.Name(), err)
+			}
+			text := p.Text
+			if text == "" && p.Comment != nil {
+				// file_comment posts carry their text in "comment" instead
+				// of "text"; callers that don't skip that subtype still
+				// want something in Text.
+				text = p.Comment.Comment
+			}
+			msgs = append(msgs, slack.Message{
+				Msg: slack.Msg{
+					Type:      p.Type,
+					SubType:   p.SubType,
+					User:      p.User,
+					BotID:     p.BotId,
+					Username:  p.Username,
+					Text:      text,
+					Timestamp: ts,
+				},
+			})
+		}
+	}
+	return msgs, nil
+}
+
+func (im *Import) decodeJSON(name string, v any) error {
+	f, err := im.src.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := json.NewDecoder(f).Decode(v); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
+
+// groupPostsByDate buckets msgs by the date of their Slack timestamp, the
+// same layout saveChannel expects.
+func groupPostsByDate(msgs []slack.Message) (messagesByDate, error) {
+	byDate := make(messagesByDate)
+	for _, m := range msgs {
+		date, err := tsToDate(m.Timestamp)
+		if err != nil {
+			return nil, err
+		}
+		byDate[date] = append(byDate[date], slackdump.Message{Message: m})
+	}
+	for date := range byDate {
+		sort.Slice(byDate[date], func(i, j int) bool {
+			return byDate[date][i].Timestamp < byDate[date][j].Timestamp
+		})
+	}
+	return byDate, nil
+}
+
+// tsToDate returns the YYYY-MM-DD date a Slack "sec.subsec" timestamp falls
+// on, matching the per-date file naming saveChannel uses.
+func tsToDate(ts string) (string, error) {
+	sec := strings.SplitN(ts, ".", 2)[0]
+	unix, err := strconv.ParseInt(sec, 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("invalid timestamp %q: %w", ts, err)
+	}
+	return time.Unix(unix, 0).UTC().Format("2006-01-02"), nil
+}
+
+// parseExportTS validates that ts is a well-formed "sec.subsec" Slack
+// timestamp and passes it through unchanged: that's already the form
+// slackdump.Message.Timestamp expects, so no conversion is needed to
+// round-trip it.
+func parseExportTS(ts string) (string, error) {
+	sec := strings.SplitN(ts, ".", 2)[0]
+	if _, err := strconv.ParseInt(sec, 10, 64); err != nil {
+		return "", fmt.Errorf("invalid timestamp %q: %w", ts, err)
+	}
+	return ts, nil
+}
+
+// normalizeChannelName mirrors the trimming the official Slack export
+// applies to directory names: leading/trailing "_-" are stripped, and
+// single-character names are prefixed to avoid clashing with reserved
+// filenames.
+func normalizeChannelName(id, name string) string {
+	trimmed := strings.Trim(name, "_-")
+	if trimmed == "" {
+		return "slack-channel-" + id
+	}
+	if len([]rune(trimmed)) == 1 {
+		return "slack-channel-" + trimmed
+	}
+	return trimmed
+}
@@ -0,0 +1,176 @@
+package export
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rusq/slackdump/v2"
+)
+
+// stateFilename is the name of the resumable-export state file, written at
+// the root of the export directory.
+const stateFilename = "state.json"
+
+// channelState tracks how far a single channel's export has progressed, so
+// that a subsequent run can resume from where the last one left off instead
+// of re-downloading everything.
+type channelState struct {
+	LatestTS string `json:"latest_ts"`
+
+	// CompletedDates lists the per-date files (see saveChannel) that are
+	// known to be complete, i.e. that can no longer receive older messages
+	// in a later run: since LatestTS only ever advances forward, every date
+	// strictly before the date LatestTS falls on is final the moment it's
+	// recorded. saveChannel consults this to skip re-merging and rewriting
+	// those files on every run.
+	CompletedDates []string `json:"completed_dates,omitempty"`
+}
+
+// exportState is the on-disk, resumable state of an export run, keyed by
+// channel ID.
+type exportState struct {
+	path string
+	mu   sync.Mutex
+
+	Channels map[string]*channelState `json:"channels"`
+}
+
+// loadState reads path, returning a fresh, empty state if it doesn't exist
+// yet.
+func loadState(path string) (*exportState, error) {
+	st := &exportState{path: path, Channels: make(map[string]*channelState)}
+
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return st, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("state: %w", err)
+	}
+	defer f.Close()
+
+	if err := json.NewDecoder(f).Decode(st); err != nil {
+		return nil, fmt.Errorf("state: failed to parse %q: %w", path, err)
+	}
+	if st.Channels == nil {
+		st.Channels = make(map[string]*channelState)
+	}
+	return st, nil
+}
+
+// get returns a copy of the recorded state for channelID, or the zero value
+// if the channel hasn't been seen before.
+func (st *exportState) get(channelID string) channelState {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if cs, ok := st.Channels[channelID]; ok {
+		return *cs
+	}
+	return channelState{}
+}
+
+// update applies fn to channelID's state, creating it if necessary.
+func (st *exportState) update(channelID string, fn func(cs *channelState)) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	cs, ok := st.Channels[channelID]
+	if !ok {
+		cs = &channelState{}
+		st.Channels[channelID] = cs
+	}
+	fn(cs)
+}
+
+// noteLatest records ts as the channel's latest_ts, if it is newer than what
+// is already recorded.
+func (st *exportState) noteLatest(channelID, ts string) {
+	if ts == "" {
+		return
+	}
+	st.update(channelID, func(cs *channelState) {
+		if ts > cs.LatestTS {
+			cs.LatestTS = ts
+		}
+	})
+}
+
+// completedDateSet returns the channel's recorded completed_dates as a set,
+// for saveChannel to consult before rewriting a date file.
+func (st *exportState) completedDateSet(channelID string) map[string]bool {
+	dates := st.get(channelID).CompletedDates
+	set := make(map[string]bool, len(dates))
+	for _, d := range dates {
+		set[d] = true
+	}
+	return set
+}
+
+// noteCompletedDates merges dates into the channel's completed_dates list.
+func (st *exportState) noteCompletedDates(channelID string, dates []string) {
+	st.update(channelID, func(cs *channelState) {
+		seen := make(map[string]bool, len(cs.CompletedDates))
+		for _, d := range cs.CompletedDates {
+			seen[d] = true
+		}
+		for _, d := range dates {
+			if !seen[d] {
+				cs.CompletedDates = append(cs.CompletedDates, d)
+				seen[d] = true
+			}
+		}
+		sort.Strings(cs.CompletedDates)
+	})
+}
+
+// flush atomically writes the state to disk, so that a Ctrl-C between calls
+// never leaves a half-written state.json.
+func (st *exportState) flush() error {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	return atomicSerializeToFile(st.path, st)
+}
+
+// latestTimestamp returns the largest (i.e. most recent) Timestamp among
+// msgs, or "" if msgs is empty.
+func latestTimestamp(msgs []slackdump.Message) string {
+	var latest string
+	for _, m := range msgs {
+		if m.Timestamp > latest {
+			latest = m.Timestamp
+		}
+	}
+	return latest
+}
+
+// tsToOldest turns a recorded latest_ts into a time.Time suitable for
+// DumpMessagesRaw's oldest parameter.
+func tsToOldest(ts string) (time.Time, error) {
+	if ts == "" {
+		return time.Time{}, nil
+	}
+	sec, err := strconv.ParseInt(strings.SplitN(ts, ".", 2)[0], 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid timestamp %q: %w", ts, err)
+	}
+	return time.Unix(sec, 0), nil
+}
+
+// atomicSerializeToFile writes data as indented JSON to a temp file next to
+// filename and renames it into place, so readers never observe a partial
+// write.
+func atomicSerializeToFile(filename string, data any) error {
+	tmp := filename + ".tmp"
+	if err := serializeToFile(tmp, data); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, filename); err != nil {
+		return fmt.Errorf("atomicSerializeToFile: failed to rename %q: %w", tmp, err)
+	}
+	return nil
+}
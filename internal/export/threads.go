@@ -0,0 +1,92 @@
+package export
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/slack-go/slack"
+
+	"github.com/rusq/slackdump/v2"
+	"github.com/rusq/slackdump/v2/downloader"
+)
+
+// attachThreads walks every message in msgs and, for each thread root (a
+// message whose ThreadTimestamp equals its own Timestamp and whose
+// ReplyCount is non-zero), fetches the full reply chain and embeds it
+// inline, matching the shape of the official Slack export: the root gets a
+// Replies field of {user, ts} pairs, and every reply is additionally
+// inserted into msgs under the date of its own ts, with its ThreadTimestamp
+// pointing back at the root.
+func (se *Export) attachThreads(ctx context.Context, ch slack.Channel, msgs messagesByDate, dl *downloader.Client) error {
+	dlFn := se.downloadFn(dl, ch.Name)
+
+	// Replies, whatever date they fall on, are collected here instead of
+	// being appended straight into msgs: a
+	// later root on that same date still needs dayMsgs[i] to be a stable
+	// index into the slice we're writing Replies back into, and appending
+	// to msgs[date] while iterating it can reallocate that slice out from
+	// under an earlier root's in-progress write.
+	pendingChildren := make(messagesByDate)
+
+	for date, dayMsgs := range msgs {
+		for i := range dayMsgs {
+			parent := dayMsgs[i]
+			if parent.ThreadTimestamp != parent.Timestamp || parent.ReplyCount == 0 {
+				continue
+			}
+
+			replies, err := se.dumper.DumpThreadRaw(ctx, ch.ID, parent.Timestamp, dlFn)
+			if err != nil {
+				return fmt.Errorf("thread %s: %w", parent.Timestamp, err)
+			}
+
+			var threadReplies []slack.Reply
+			for _, r := range replies {
+				if r.Timestamp == parent.Timestamp {
+					continue // the root itself is always included in the replies
+				}
+				if se.opts.MaxThreadDepth > 0 && len(threadReplies) >= se.opts.MaxThreadDepth {
+					break
+				}
+
+				threadReplies = append(threadReplies, slack.Reply{User: r.User, Timestamp: r.Timestamp})
+
+				childDate, err := tsToDate(r.Timestamp)
+				if err != nil {
+					return fmt.Errorf("reply %s: %w", r.Timestamp, err)
+				}
+				child := slackdump.Message{Message: r}
+				child.ThreadTimestamp = parent.Timestamp
+				pendingChildren[childDate] = append(pendingChildren[childDate], child)
+			}
+
+			// dayMsgs shares msgs[date]'s backing array, so writing by
+			// index here is visible through msgs too, without needing a
+			// pointer into a slice that's being appended to elsewhere.
+			dayMsgs[i].Replies = threadReplies
+		}
+	}
+
+	for date, children := range pendingChildren {
+		msgs[date] = append(msgs[date], children...)
+	}
+	return nil
+}
+
+// filterThreadsOnly drops every message that is neither a thread root nor a
+// reply, keeping only conversations that are part of a thread.
+func filterThreadsOnly(msgs messagesByDate) messagesByDate {
+	filtered := make(messagesByDate, len(msgs))
+	for date, dayMsgs := range msgs {
+		var kept []slackdump.Message
+		for _, m := range dayMsgs {
+			if m.ReplyCount > 0 || m.ThreadTimestamp != "" {
+				kept = append(kept, m)
+			}
+		}
+		if len(kept) > 0 {
+			filtered[date] = kept
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,76 @@
+// Package exporter defines the pluggable export backend interface that
+// slackdump's various output formats (the Slack-compatible export, the
+// Mattermost bulk importer, and others) implement, and a registry for
+// selecting one of them by name at runtime.
+package exporter
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rusq/slackdump/v2"
+)
+
+// Exporter is implemented by every export backend.  Run performs the export
+// into the directory the backend was constructed with, returning once it's
+// complete or ctx is cancelled.
+type Exporter interface {
+	Run(ctx context.Context) error
+}
+
+// Options configures an export run independently of which backend produces
+// it.
+type Options struct {
+	Oldest         time.Time
+	Latest         time.Time
+	IncludeFiles   bool
+	IncludePrivate bool
+}
+
+// Factory creates an Exporter that writes to dir, pulling data from Slack
+// through dumper.
+type Factory func(dir string, dumper *slackdump.SlackDumper, opts Options) (Exporter, error)
+
+var (
+	mu       sync.Mutex
+	registry = make(map[string]Factory)
+)
+
+// Register adds factory under name, so that New(name, ...) can construct it.
+// It panics if name is already registered, the same behaviour database/sql
+// drivers use.
+func Register(name string, factory Factory) {
+	mu.Lock()
+	defer mu.Unlock()
+	if _, exists := registry[name]; exists {
+		panic("exporter: Register called twice for backend " + name)
+	}
+	registry[name] = factory
+}
+
+// New constructs the Exporter registered under name.
+func New(name, dir string, dumper *slackdump.SlackDumper, opts Options) (Exporter, error) {
+	mu.Lock()
+	factory, ok := registry[name]
+	mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("exporter: unknown format %q (known formats: %s)", name, strings.Join(Formats(), ", "))
+	}
+	return factory(dir, dumper, opts)
+}
+
+// Formats returns the names of all registered backends, sorted.
+func Formats() []string {
+	mu.Lock()
+	defer mu.Unlock()
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,130 @@
+package exporter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/slack-go/slack"
+
+	"github.com/rusq/slackdump/v2"
+	"github.com/rusq/slackdump/v2/downloader"
+)
+
+func init() {
+	Register("zulip", newGenericExporter("zulip"))
+	Register("discord", newGenericExporter("discord"))
+}
+
+// genericChannel is the per-channel JSON document written by
+// genericExporter: a minimal, tool-agnostic shape that's close enough to
+// both Zulip's and Discord's own import formats to be transformed the rest
+// of the way by a small script.
+type genericChannel struct {
+	Format   string           `json:"format"`
+	Name     string           `json:"name"`
+	Topic    string           `json:"topic,omitempty"`
+	Messages []genericMessage `json:"messages"`
+}
+
+type genericMessage struct {
+	User      string   `json:"user"`
+	Text      string   `json:"text"`
+	Timestamp int64    `json:"timestamp"`
+	Files     []string `json:"files,omitempty"`
+}
+
+// genericExporter writes one JSON file per channel in the shape above. The
+// format itself is a single document per channel, so unlike the Mattermost
+// backend, which encodes each page as it arrives, it keeps every converted
+// message of a channel in memory until the channel's file is written.
+type genericExporter struct {
+	format string
+	dir    string
+	dumper *slackdump.SlackDumper
+	opts   Options
+}
+
+func newGenericExporter(format string) Factory {
+	return func(dir string, dumper *slackdump.SlackDumper, opts Options) (Exporter, error) {
+		return &genericExporter{format: format, dir: dir, dumper: dumper, opts: opts}, nil
+	}
+}
+
+func (ge *genericExporter) Run(ctx context.Context) error {
+	users, err := ge.dumper.GetUsers(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: users: %w", ge.format, err)
+	}
+	uidx := users.IndexByID()
+
+	dl := downloader.New(ge.dumper.Client())
+	if ge.opts.IncludeFiles {
+		dl.Start(ctx)
+	}
+
+	return ge.dumper.StreamChannels(ctx, slackdump.AllChanTypes, func(ch slack.Channel) error {
+		if err := ge.exportChannel(ctx, ch, uidx, dl); err != nil {
+			return fmt.Errorf("%s: channel %q: %w", ge.format, ch.Name, err)
+		}
+		return nil
+	})
+}
+
+func (ge *genericExporter) exportChannel(ctx context.Context, ch slack.Channel, uidx map[string]*slack.User, dl *downloader.Client) error {
+	name := channelDirName(ch, uidx)
+	dir := filepath.Join(ge.dir, name, "attachments")
+
+	var msgs []genericMessage
+	_, err := ge.dumper.DumpMessagesRaw(ctx, ch.ID, ge.opts.Oldest, ge.opts.Latest, func(raw []slackdump.Message, channelID string) (slackdump.ProcessResult, error) {
+		for _, m := range raw {
+			ts, err := slackTSToUnixMillis(m.Timestamp)
+			if err != nil {
+				return slackdump.ProcessResult{}, err
+			}
+
+			var files []string
+			for _, file := range ge.dumper.ExtractFiles([]slackdump.Message{m}) {
+				if err := dl.DownloadFile(dir, file); err != nil {
+					if errors.Is(err, downloader.ErrNotStarted) {
+						continue
+					}
+					return slackdump.ProcessResult{}, err
+				}
+				files = append(files, filepath.Join(name, "attachments", file.Name))
+			}
+
+			msgs = append(msgs, genericMessage{
+				User:      mmUsername(m.User, uidx),
+				Text:      rewriteMentions(m.Text, uidx),
+				Timestamp: ts,
+				Files:     files,
+			})
+		}
+		return slackdump.ProcessResult{Entity: "messages", Count: len(raw)}, nil
+	})
+	if err != nil {
+		return err
+	}
+	if len(msgs) == 0 {
+		return nil
+	}
+
+	f, err := os.Create(filepath.Join(ge.dir, name+".json"))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "  ")
+	return enc.Encode(genericChannel{
+		Format:   ge.format,
+		Name:     name,
+		Topic:    ch.Topic.Value,
+		Messages: msgs,
+	})
+}
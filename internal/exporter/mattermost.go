@@ -0,0 +1,282 @@
+package exporter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/slack-go/slack"
+
+	"github.com/rusq/slackdump/v2"
+	"github.com/rusq/slackdump/v2/downloader"
+)
+
+func init() {
+	Register("mattermost", newMattermostExporter)
+}
+
+// mentionRE matches Slack's "<@U012ABCDEF>" mention tokens so they can be
+// rewritten as Mattermost "@username" mentions.
+var mentionRE = regexp.MustCompile(`<@([A-Z0-9]+)>`)
+
+// mattermostExporter writes the dumped conversations as a single Mattermost
+// bulk-import JSONL file (one JSON object per line: a "version" and "team"
+// header, then "channel", "user", "post", "direct_channel" and
+// "direct_post" entries), suitable for `mmctl import bulk`.
+type mattermostExporter struct {
+	dir    string
+	dumper *slackdump.SlackDumper
+	opts   Options
+	team   string
+
+	// selfID is the authenticated user's ID, resolved once in Run and used
+	// to fill in the other half of an IM's membership (the Slack API
+	// doesn't return Members for IMs, only the counterpart's ID in User).
+	selfID string
+}
+
+func newMattermostExporter(dir string, dumper *slackdump.SlackDumper, opts Options) (Exporter, error) {
+	return &mattermostExporter{dir: dir, dumper: dumper, opts: opts, team: "slackdump-import"}, nil
+}
+
+type mmLine struct {
+	Type          string        `json:"type"`
+	Version       int           `json:"version,omitempty"`
+	Team          *mmTeam       `json:"team,omitempty"`
+	Channel       *mmChannel    `json:"channel,omitempty"`
+	User          *mmUser       `json:"user,omitempty"`
+	Post          *mmPost       `json:"post,omitempty"`
+	DirectChannel *mmDirect     `json:"direct_channel,omitempty"`
+	DirectPost    *mmDirectPost `json:"direct_post,omitempty"`
+}
+
+type mmTeam struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+	Type        string `json:"type"`
+}
+
+type mmChannel struct {
+	Team        string `json:"team"`
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+	Type        string `json:"type"`
+	Header      string `json:"header,omitempty"`
+	Purpose     string `json:"purpose,omitempty"`
+}
+
+type mmUser struct {
+	Username string `json:"username"`
+	Email    string `json:"email"`
+}
+
+type mmPost struct {
+	Team        string         `json:"team"`
+	Channel     string         `json:"channel"`
+	User        string         `json:"user"`
+	Message     string         `json:"message"`
+	CreateAt    int64          `json:"create_at"`
+	Attachments []mmAttachment `json:"attachments,omitempty"`
+}
+
+type mmDirect struct {
+	Members []string `json:"members"`
+}
+
+type mmDirectPost struct {
+	ChannelMembers []string       `json:"channel_members"`
+	User           string         `json:"user"`
+	Message        string         `json:"message"`
+	CreateAt       int64          `json:"create_at"`
+	Attachments    []mmAttachment `json:"attachments,omitempty"`
+}
+
+type mmAttachment struct {
+	Path string `json:"path"`
+}
+
+// Run streams every channel's messages through DumpMessagesRaw's process
+// callback and encodes each page onto the JSONL file as it arrives.
+// DumpMessagesRaw itself still collects the channel's full message list for
+// its return value, which Run discards.
+func (me *mattermostExporter) Run(ctx context.Context) error {
+	users, err := me.dumper.GetUsers(ctx)
+	if err != nil {
+		return fmt.Errorf("mattermost: users: %w", err)
+	}
+	uidx := users.IndexByID()
+
+	self, err := me.dumper.Client().AuthTestContext(ctx)
+	if err != nil {
+		return fmt.Errorf("mattermost: auth test: %w", err)
+	}
+	me.selfID = self.UserID
+
+	f, err := os.Create(filepath.Join(me.dir, "mattermost_import.jsonl"))
+	if err != nil {
+		return fmt.Errorf("mattermost: %w", err)
+	}
+	defer f.Close()
+
+	enc := json.NewEncoder(f)
+	if err := enc.Encode(mmLine{Type: "version", Version: 1}); err != nil {
+		return fmt.Errorf("mattermost: %w", err)
+	}
+	if err := enc.Encode(mmLine{Type: "team", Team: &mmTeam{Name: me.team, DisplayName: me.team, Type: "O"}}); err != nil {
+		return fmt.Errorf("mattermost: %w", err)
+	}
+	for _, u := range users {
+		if err := enc.Encode(mmLine{Type: "user", User: &mmUser{Username: u.Name, Email: u.Profile.Email}}); err != nil {
+			return fmt.Errorf("mattermost: %w", err)
+		}
+	}
+
+	dl := downloader.New(me.dumper.Client())
+	if me.opts.IncludeFiles {
+		dl.Start(ctx)
+	}
+
+	return me.dumper.StreamChannels(ctx, slackdump.AllChanTypes, func(ch slack.Channel) error {
+		if err := me.exportChannel(ctx, enc, ch, uidx, dl); err != nil {
+			return fmt.Errorf("mattermost: channel %q: %w", ch.Name, err)
+		}
+		return nil
+	})
+}
+
+func (me *mattermostExporter) exportChannel(ctx context.Context, enc *json.Encoder, ch slack.Channel, uidx map[string]*slack.User, dl *downloader.Client) error {
+	isDirect := ch.IsIM || ch.IsMpIM
+	if isDirect && len(ch.Members) == 0 {
+		// The Slack API doesn't populate Members for IMs, only the
+		// counterpart's ID in User -- fill in both sides ourselves so
+		// channel_members isn't empty, which mmctl import bulk rejects.
+		ch.Members = []string{me.selfID, ch.User}
+	}
+	if !isDirect {
+		if err := enc.Encode(mmLine{Type: "channel", Channel: &mmChannel{
+			Team:        me.team,
+			Name:        ch.NameNormalized,
+			DisplayName: ch.Name,
+			Type:        mmChannelType(ch),
+			Header:      ch.Topic.Value,
+			Purpose:     ch.Purpose.Value,
+		}}); err != nil {
+			return err
+		}
+	} else {
+		if err := enc.Encode(mmLine{Type: "direct_channel", DirectChannel: &mmDirect{Members: ch.Members}}); err != nil {
+			return err
+		}
+	}
+
+	dir := filepath.Join(me.dir, channelDirName(ch, uidx), "attachments")
+	_, err := me.dumper.DumpMessagesRaw(ctx, ch.ID, me.opts.Oldest, me.opts.Latest, func(msgs []slackdump.Message, channelID string) (slackdump.ProcessResult, error) {
+		for _, m := range msgs {
+			line, err := me.toMMLine(ch, m, uidx, dl, dir, isDirect)
+			if err != nil {
+				return slackdump.ProcessResult{}, err
+			}
+			if err := enc.Encode(line); err != nil {
+				return slackdump.ProcessResult{}, err
+			}
+		}
+		return slackdump.ProcessResult{Entity: "messages", Count: len(msgs)}, nil
+	})
+	return err
+}
+
+func (me *mattermostExporter) toMMLine(ch slack.Channel, m slackdump.Message, uidx map[string]*slack.User, dl *downloader.Client, attachDir string, isDirect bool) (mmLine, error) {
+	createAt, err := slackTSToUnixMillis(m.Timestamp)
+	if err != nil {
+		return mmLine{}, err
+	}
+
+	var attachments []mmAttachment
+	for _, file := range me.dumper.ExtractFiles([]slackdump.Message{m}) {
+		if err := dl.DownloadFile(attachDir, file); err != nil {
+			if errors.Is(err, downloader.ErrNotStarted) {
+				continue
+			}
+			return mmLine{}, err
+		}
+		attachments = append(attachments, mmAttachment{Path: filepath.Join(channelDirName(ch, uidx), "attachments", file.Name)})
+	}
+
+	message := rewriteMentions(m.Text, uidx)
+	user := mmUsername(m.User, uidx)
+
+	if isDirect {
+		return mmLine{Type: "direct_post", DirectPost: &mmDirectPost{
+			ChannelMembers: ch.Members,
+			User:           user,
+			Message:        message,
+			CreateAt:       createAt,
+			Attachments:    attachments,
+		}}, nil
+	}
+
+	return mmLine{Type: "post", Post: &mmPost{
+		Team:        me.team,
+		Channel:     ch.NameNormalized,
+		User:        user,
+		Message:     message,
+		CreateAt:    createAt,
+		Attachments: attachments,
+	}}, nil
+}
+
+// slackTSToUnixMillis converts a Slack "sec.subsec" timestamp to
+// milliseconds since epoch, the unit Mattermost's bulk import expects.
+func slackTSToUnixMillis(ts string) (int64, error) {
+	sec, err := strconv.ParseInt(strings.SplitN(ts, ".", 2)[0], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid timestamp %q: %w", ts, err)
+	}
+	return sec * 1000, nil
+}
+
+// rewriteMentions replaces Slack's "<@U012ABCDEF>" mention tokens with
+// Mattermost-style "@username" mentions, falling back to the raw ID when
+// the user isn't in the index.
+func rewriteMentions(text string, uidx map[string]*slack.User) string {
+	return mentionRE.ReplaceAllStringFunc(text, func(tok string) string {
+		id := mentionRE.FindStringSubmatch(tok)[1]
+		return "@" + mmUsername(id, uidx)
+	})
+}
+
+func mmUsername(userID string, uidx map[string]*slack.User) string {
+	if u, ok := uidx[userID]; ok {
+		return u.Name
+	}
+	return userID
+}
+
+// dmDirPrefix prefixes the synthesised directory/channel name generic and
+// mattermost exporters use for IMs, mirroring internal/export's userPrefix.
+const dmDirPrefix = "IM-"
+
+// channelDirName returns the name exportChannel should file ch's messages
+// and attachments under. IMs have an empty NameNormalized, so
+// without this they'd all collide on the same path; resolve them to the
+// counterpart's username instead, the same way internal/export's validName
+// does for the Slack-compatible export.
+func channelDirName(ch slack.Channel, uidx map[string]*slack.User) string {
+	if ch.NameNormalized != "" {
+		return ch.NameNormalized
+	}
+	return dmDirPrefix + mmUsername(ch.User, uidx)
+}
+
+func mmChannelType(ch slack.Channel) string {
+	if ch.IsPrivate {
+		return "P"
+	}
+	return "O"
+}
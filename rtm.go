@@ -0,0 +1,60 @@
+package slackdump
+
+import (
+	"context"
+	"time"
+
+	"github.com/rusq/dlog"
+	"github.com/slack-go/slack"
+)
+
+// minRTMBackoff and maxRTMBackoff bound the exponentially growing backoff
+// value Stream logs on successive RTM disconnects; Stream does not wait on it.
+const (
+	minRTMBackoff = time.Second
+	maxRTMBackoff = time.Minute
+)
+
+// StreamHandler processes a single RTM event.  Returning an error stops
+// Stream.
+type StreamHandler func(evt slack.RTMEvent) error
+
+// Stream opens a real-time (RTM) connection, sharing the same token and
+// cookies the REST client was constructed with, and calls handler for every
+// event received until ctx is cancelled or handler returns an error.
+//
+// The underlying RTM client reconnects on its own; Stream additionally logs
+// each disconnect with an increasing backoff, purely for visibility, since
+// rtm.ManageConnection already paces the actual reconnect attempts.
+func (sd *SlackDumper) Stream(ctx context.Context, handler StreamHandler) error {
+	rtm := sd.Client().NewRTM()
+	go rtm.ManageConnection()
+	defer rtm.Disconnect()
+
+	backoff := minRTMBackoff
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case evt, ok := <-rtm.IncomingEvents:
+			if !ok {
+				return nil
+			}
+
+			switch evt.Data.(type) {
+			case *slack.ConnectedEvent:
+				backoff = minRTMBackoff
+			case *slack.DisconnectedEvent:
+				dlog.Printf("rtm: disconnected, reconnecting (backoff %s)", backoff)
+				backoff *= 2
+				if backoff > maxRTMBackoff {
+					backoff = maxRTMBackoff
+				}
+			}
+
+			if err := handler(evt); err != nil {
+				return err
+			}
+		}
+	}
+}
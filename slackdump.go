@@ -44,6 +44,7 @@ type clienter interface {
 	GetConversationHistoryContext(ctx context.Context, params *slack.GetConversationHistoryParameters) (*slack.GetConversationHistoryResponse, error)
 	GetConversationRepliesContext(ctx context.Context, params *slack.GetConversationRepliesParameters) (msgs []slack.Message, hasMore bool, nextCursor string, err error)
 	GetConversationsContext(ctx context.Context, params *slack.GetConversationsParameters) (channels []slack.Channel, nextCursor string, err error)
+	GetUsersInConversationContext(ctx context.Context, params *slack.GetUsersInConversationParameters) (members []string, nextCursor string, err error)
 	GetFile(downloadURL string, writer io.Writer) error
 	GetTeamInfo() (*slack.TeamInfo, error)
 	GetUsersContext(ctx context.Context) ([]slack.User, error)
@@ -116,6 +117,83 @@ func (sd *SlackDumper) limiter(t network.Tier) *rate.Limiter {
 	return network.NewLimiter(t, sd.options.Tier3Burst, int(sd.options.Tier3Boost))
 }
 
+// maxMemberFetchAttempts is the number of retries withRetry will perform for
+// a single conversations.members page before giving up.
+const maxMemberFetchAttempts = 3
+
+// FetchPrivateChannels retrieves all private channels ("groups") the
+// authenticated user is a member of, along with their full member lists.  It
+// paginates through conversations.list and conversations.members, honouring
+// the Tier-2 rate limiter on every call.
+func (sd *SlackDumper) FetchPrivateChannels(ctx context.Context) ([]slack.Channel, error) {
+	ctx, task := trace.NewTask(ctx, "FetchPrivateChannels")
+	defer task.End()
+
+	limiter := sd.limiter(network.Tier2)
+
+	var channels []slack.Channel
+	params := &slack.GetConversationsParameters{Types: []string{"private_channel"}}
+	for {
+		var (
+			page []slack.Channel
+			next string
+		)
+		if err := withRetry(ctx, limiter, maxMemberFetchAttempts, func() error {
+			var err error
+			page, next, err = sd.client.GetConversationsContext(ctx, params)
+			return err
+		}); err != nil {
+			return nil, fmt.Errorf("error fetching private channels: %w", err)
+		}
+		channels = append(channels, page...)
+		if next == "" {
+			break
+		}
+		params.Cursor = next
+	}
+
+	for i := range channels {
+		members, err := sd.fetchChannelMembers(ctx, channels[i].ID, limiter)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching members of %q: %w", channels[i].ID, err)
+		}
+		channels[i].Members = members
+	}
+
+	return channels, nil
+}
+
+// fetchChannelMembers retrieves the full, de-paginated member list of the
+// given channel.
+func (sd *SlackDumper) fetchChannelMembers(ctx context.Context, channelID string, limiter *rate.Limiter) ([]string, error) {
+	var (
+		members []string
+		cursor  string
+	)
+	for {
+		var (
+			page []string
+			next string
+		)
+		if err := withRetry(ctx, limiter, maxMemberFetchAttempts, func() error {
+			var err error
+			page, next, err = sd.client.GetUsersInConversationContext(ctx, &slack.GetUsersInConversationParameters{
+				ChannelID: channelID,
+				Cursor:    cursor,
+			})
+			return err
+		}); err != nil {
+			return nil, err
+		}
+		members = append(members, page...)
+		if next == "" {
+			break
+		}
+		cursor = next
+	}
+	return members, nil
+}
+
 // withRetry will run the callback function fn. If the function returns
 // slack.RateLimitedError, it will delay, and then call it again up to
 // maxAttempts times. It will return an error if it runs out of attempts.
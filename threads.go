@@ -0,0 +1,68 @@
+package slackdump
+
+import (
+	"context"
+	"fmt"
+	"runtime/trace"
+
+	"github.com/slack-go/slack"
+
+	"github.com/rusq/slackdump/v2/internal/network"
+)
+
+// maxThreadPageAttempts bounds how many times withRetry retries a single
+// conversations.replies page before giving up.
+const maxThreadPageAttempts = 3
+
+// DumpThreadRaw fetches the full reply chain of the thread rooted at
+// threadTS in channelID, paginating through conversations.replies on the
+// Tier-3 limiter.  processFn, if not nil, is called for every page exactly
+// as DumpMessagesRaw calls it for a flat timeline, so the same download
+// hooks fire on files attached to replies.
+func (sd *SlackDumper) DumpThreadRaw(ctx context.Context, channelID, threadTS string, processFn func(msg []Message, channelID string) (ProcessResult, error)) ([]slack.Message, error) {
+	ctx, task := trace.NewTask(ctx, "DumpThreadRaw")
+	defer task.End()
+
+	limiter := sd.limiter(network.Tier3)
+
+	var (
+		replies []slack.Message
+		cursor  string
+	)
+	for {
+		var (
+			page    []slack.Message
+			hasMore bool
+			next    string
+		)
+		if err := withRetry(ctx, limiter, maxThreadPageAttempts, func() error {
+			var err error
+			page, hasMore, next, err = sd.client.GetConversationRepliesContext(ctx, &slack.GetConversationRepliesParameters{
+				ChannelID: channelID,
+				Timestamp: threadTS,
+				Cursor:    cursor,
+			})
+			return err
+		}); err != nil {
+			return nil, fmt.Errorf("error fetching replies for thread %s: %w", threadTS, err)
+		}
+
+		if processFn != nil {
+			msgs := make([]Message, len(page))
+			for i := range page {
+				msgs[i] = Message{Message: page[i]}
+			}
+			if _, err := processFn(msgs, channelID); err != nil {
+				return nil, err
+			}
+		}
+
+		replies = append(replies, page...)
+		if !hasMore || next == "" {
+			break
+		}
+		cursor = next
+	}
+
+	return replies, nil
+}